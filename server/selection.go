@@ -2,16 +2,29 @@ package server
 
 import (
 	"container/heap"
+	"errors"
+	"math"
 	"math/rand"
+	"sync"
+	"time"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/golang/glog"
 )
 
+// ErrOrchestratorProtocol should be wrapped or returned by callers of Failed to indicate
+// that a failure was caused by a protocol or authentication error rather than a transient
+// network error. CircuitBreakerSelector evicts an orchestrator permanently on such an error
+// instead of quarantining it
+var ErrOrchestratorProtocol = errors.New("orchestrator protocol or auth error")
+
 // BroadcastSessionsSelector selects the next BroadcastSession to use
 type BroadcastSessionsSelector interface {
 	Add(sessions []*BroadcastSession)
 	Complete(sess *BroadcastSession)
+	// Failed reports that a session could not be used to complete a segment, along with
+	// the error that caused the failure, so that future selections can account for it
+	Failed(sess *BroadcastSession, err error)
 	Select() *BroadcastSession
 	Size() int
 	Clear()
@@ -23,6 +36,7 @@ func (h sessHeap) Len() int {
 	return len(h)
 }
 
+// Less compares the EWMA latency scores maintained on each session by MinLSSelector.Complete
 func (h sessHeap) Less(i, j int) bool {
 	return h[i].LatencyScore < h[j].LatencyScore
 }
@@ -64,8 +78,46 @@ type stakeReader interface {
 	Stakes(addrs []ethcommon.Address) (map[ethcommon.Address]int64, error)
 }
 
+const (
+	// defaultAlpha is the default EWMA smoothing factor used by MinLSSelector
+	defaultAlpha = 0.3
+	// defaultTTL is the default duration after which a known session's latency score
+	// is considered stale by MinLSSelector
+	defaultTTL = 5 * time.Minute
+)
+
+// lsHistory tracks the EWMA latency score last observed for an orchestrator and when
+// it was last observed, so that stale scores can be decayed back to unknown
+type lsHistory struct {
+	score    float64
+	lastSeen time.Time
+}
+
+// MinLSSelectorOption configures optional parameters of a MinLSSelector
+type MinLSSelectorOption func(*MinLSSelector)
+
+// WithAlpha sets the EWMA smoothing factor applied to each new latency sample
+// (score = alpha*newSample + (1-alpha)*prevScore). The default is 0.3
+func WithAlpha(alpha float64) MinLSSelectorOption {
+	return func(s *MinLSSelector) {
+		s.alpha = alpha
+	}
+}
+
+// WithTTL sets the duration after which a known session's latency score is considered
+// stale; stale sessions are moved back to the pool of unknown sessions on the next
+// Select() call. The default is 5 minutes
+func WithTTL(ttl time.Duration) MinLSSelectorOption {
+	return func(s *MinLSSelector) {
+		s.ttl = ttl
+	}
+}
+
 // MinLSSelector selects the next BroadcastSession with the lowest latency score if it is good enough.
-// Otherwise, it selects a session that does not have a latency score yet
+// Otherwise, it selects a session that does not have a latency score yet.
+// The latency score of a session is an EWMA over its historical samples so that a single bad
+// segment does not permanently deprioritize an otherwise good orchestrator, and scores older
+// than a configurable TTL are decayed back to unknown so they are re-evaluated from scratch.
 // MinLSSelector is not concurrency safe so the caller is responsible for ensuring safety for concurrent method calls
 type MinLSSelector struct {
 	unknownSessions []*BroadcastSession
@@ -74,18 +126,33 @@ type MinLSSelector struct {
 	stakeRdr stakeReader
 
 	minLS float64
+
+	alpha   float64
+	ttl     time.Duration
+	history map[ethcommon.Address]*lsHistory
 }
 
-// NewMinLSSelector returns an instance of MinLSSelector configured with a good enough latency score
-func NewMinLSSelector(stakeRdr stakeReader, minLS float64) *MinLSSelector {
+// NewMinLSSelector returns an instance of MinLSSelector configured with a good enough latency score.
+// The EWMA smoothing factor and staleness TTL default to 0.3 and 5 minutes respectively and can be
+// overridden with WithAlpha and WithTTL
+func NewMinLSSelector(stakeRdr stakeReader, minLS float64, opts ...MinLSSelectorOption) *MinLSSelector {
 	knownSessions := &sessHeap{}
 	heap.Init(knownSessions)
 
-	return &MinLSSelector{
+	s := &MinLSSelector{
 		knownSessions: knownSessions,
 		stakeRdr:      stakeRdr,
 		minLS:         minLS,
+		alpha:         defaultAlpha,
+		ttl:           defaultTTL,
+		history:       make(map[ethcommon.Address]*lsHistory),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
 // Add adds the sessions to the selector's list of sessions without a latency score
@@ -93,14 +160,33 @@ func (s *MinLSSelector) Add(sessions []*BroadcastSession) {
 	s.unknownSessions = append(s.unknownSessions, sessions...)
 }
 
-// Complete adds the session to the selector's list sessions with a latency score
+// Complete updates the EWMA latency score for the session's orchestrator and adds the
+// session to the selector's list of sessions with a latency score
 func (s *MinLSSelector) Complete(sess *BroadcastSession) {
+	sess.LatencyScore = s.updateHistory(sess)
 	heap.Push(s.knownSessions, sess)
 }
 
+func (s *MinLSSelector) updateHistory(sess *BroadcastSession) float64 {
+	addr := ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient)
+
+	h, ok := s.history[addr]
+	if !ok {
+		h = &lsHistory{score: sess.LatencyScore}
+		s.history[addr] = h
+	} else {
+		h.score = s.alpha*sess.LatencyScore + (1-s.alpha)*h.score
+	}
+	h.lastSeen = time.Now()
+
+	return h.score
+}
+
 // Select returns the session with the lowest latency score if it is good enough.
 // Otherwise, a session without a latency score yet is returned
 func (s *MinLSSelector) Select() *BroadcastSession {
+	s.expireStale()
+
 	sess := s.knownSessions.Peek()
 	if sess == nil {
 		return s.selectUnknownSession()
@@ -114,6 +200,42 @@ func (s *MinLSSelector) Select() *BroadcastSession {
 	return heap.Pop(s.knownSessions).(*BroadcastSession)
 }
 
+// expireStale moves known sessions whose latency score was last updated more than s.ttl
+// ago back into unknownSessions so that they are re-evaluated fresh
+func (s *MinLSSelector) expireStale() {
+	now := time.Now()
+
+	fresh := (*s.knownSessions)[:0]
+	var stale []*BroadcastSession
+	for _, sess := range *s.knownSessions {
+		addr := ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient)
+
+		h, ok := s.history[addr]
+		if ok && now.Sub(h.lastSeen) > s.ttl {
+			delete(s.history, addr)
+			stale = append(stale, sess)
+			continue
+		}
+
+		fresh = append(fresh, sess)
+	}
+
+	if len(stale) == 0 {
+		return
+	}
+
+	*s.knownSessions = fresh
+	heap.Init(s.knownSessions)
+	s.unknownSessions = append(s.unknownSessions, stale...)
+}
+
+// Failed adds the session back to the selector's list of sessions without a latency score
+// so that it is re-evaluated from scratch on its next use
+func (s *MinLSSelector) Failed(sess *BroadcastSession, err error) {
+	delete(s.history, ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient))
+	s.unknownSessions = append(s.unknownSessions, sess)
+}
+
 // Size returns the number of sessions stored by the selector
 func (s *MinLSSelector) Size() int {
 	return len(s.unknownSessions) + s.knownSessions.Len()
@@ -124,6 +246,7 @@ func (s *MinLSSelector) Clear() {
 	s.unknownSessions = nil
 	s.knownSessions = &sessHeap{}
 	s.stakeRdr = nil
+	s.history = make(map[ethcommon.Address]*lsHistory)
 }
 
 // Use stake weighted random selection to select from unknownSessions
@@ -185,6 +308,13 @@ func (s *MinLSSelector) selectUnknownSession() *BroadcastSession {
 // LIFOSelector selects the next BroadcastSession in LIFO order
 type LIFOSelector []*BroadcastSession
 
+// NewLIFOSelector returns an empty LIFOSelector. It mirrors the constructor-based API of
+// the other selectors so that callers can compose them uniformly, e.g.
+// NewSyncSelector(NewLIFOSelector(), observer)
+func NewLIFOSelector() *LIFOSelector {
+	return &LIFOSelector{}
+}
+
 // Add adds the sessions to the front of the selector's list
 func (s *LIFOSelector) Add(sessions []*BroadcastSession) {
 	*s = append(sessions, *s...)
@@ -204,6 +334,11 @@ func (s *LIFOSelector) Select() *BroadcastSession {
 	return sess
 }
 
+// Failed adds the session back to the front of the selector's list
+func (s *LIFOSelector) Failed(sess *BroadcastSession, err error) {
+	*s = append([]*BroadcastSession{sess}, *s...)
+}
+
 // Size returns the number of sessions stored by the selector
 func (s *LIFOSelector) Size() int {
 	return len(*s)
@@ -213,3 +348,553 @@ func (s *LIFOSelector) Size() int {
 func (s *LIFOSelector) Clear() {
 	*s = nil
 }
+
+// SelectorConfig configures the relative importance of each criterion used by
+// WeightedSelector to score sessions. Weights do not need to sum to 1; they are
+// applied to normalized per-criterion scores before being summed
+type SelectorConfig struct {
+	// PriceWeight is applied to the normalized ticket price score (lower price scores higher)
+	PriceWeight float64
+	// LatencyWeight is applied to the normalized latency score (lower latency scores higher)
+	LatencyWeight float64
+	// StakeWeight is applied to the normalized orchestrator stake score
+	StakeWeight float64
+	// ReliabilityWeight is applied to the normalized historical success rate score
+	ReliabilityWeight float64
+	// Temperature controls softmax sampling over session scores in Select(). A Temperature
+	// <= 0 always selects the highest scoring session; higher values preserve more exploration
+	Temperature float64
+}
+
+// DefaultSelectorConfig is a SelectorConfig for NewWeightedSelector that weights all four
+// criteria equally. NewWeightedSelector does not apply it on its own
+var DefaultSelectorConfig = SelectorConfig{
+	PriceWeight:       0.25,
+	LatencyWeight:     0.25,
+	StakeWeight:       0.25,
+	ReliabilityWeight: 0.25,
+	Temperature:       0.25,
+}
+
+// reliability tracks a Beta-distributed estimate of an orchestrator's success rate.
+// Using a prior of 1 success and 1 failure ensures an orchestrator with no history
+// scores around 0.5 rather than being ranked last
+type reliability struct {
+	successes float64
+	failures  float64
+}
+
+const (
+	reliabilityPriorSuccesses = 1
+	reliabilityPriorFailures  = 1
+)
+
+func (r *reliability) score() float64 {
+	return (r.successes + reliabilityPriorSuccesses) / (r.successes + r.failures + reliabilityPriorSuccesses + reliabilityPriorFailures)
+}
+
+// WeightedSelector selects the next BroadcastSession by scoring all candidate sessions
+// on price, latency, stake and historical reliability according to a SelectorConfig and
+// sampling from a softmax over those scores. WeightedSelector is not concurrency safe so
+// the caller is responsible for ensuring safety for concurrent method calls
+type WeightedSelector struct {
+	sessions []*BroadcastSession
+
+	stakeRdr stakeReader
+	cfg      SelectorConfig
+
+	reliability map[ethcommon.Address]*reliability
+}
+
+// NewWeightedSelector returns an instance of WeightedSelector configured with cfg
+func NewWeightedSelector(stakeRdr stakeReader, cfg SelectorConfig) *WeightedSelector {
+	return &WeightedSelector{
+		stakeRdr:    stakeRdr,
+		cfg:         cfg,
+		reliability: make(map[ethcommon.Address]*reliability),
+	}
+}
+
+// Add adds the sessions to the selector's pool of candidate sessions
+func (s *WeightedSelector) Add(sessions []*BroadcastSession) {
+	s.sessions = append(s.sessions, sessions...)
+}
+
+// Complete records a successful use of sess and returns it to the selector's pool
+func (s *WeightedSelector) Complete(sess *BroadcastSession) {
+	s.reliabilityFor(sess).successes++
+	s.sessions = append(s.sessions, sess)
+}
+
+// Failed records a failed use of sess and returns it to the selector's pool
+func (s *WeightedSelector) Failed(sess *BroadcastSession, err error) {
+	s.reliabilityFor(sess).failures++
+	s.sessions = append(s.sessions, sess)
+}
+
+// Select scores every candidate session and samples from a softmax over those scores,
+// removing and returning the sampled session
+func (s *WeightedSelector) Select() *BroadcastSession {
+	if len(s.sessions) == 0 {
+		return nil
+	}
+
+	scores, ok := s.scoreSessions()
+	if !ok {
+		return nil
+	}
+
+	idx := s.sample(scores)
+	sess := s.sessions[idx]
+
+	n := len(s.sessions)
+	s.sessions[idx] = s.sessions[n-1]
+	s.sessions = s.sessions[:n-1]
+
+	return sess
+}
+
+// Size returns the number of sessions stored by the selector
+func (s *WeightedSelector) Size() int {
+	return len(s.sessions)
+}
+
+// Clear resets the selector's state
+func (s *WeightedSelector) Clear() {
+	s.sessions = nil
+	s.reliability = make(map[ethcommon.Address]*reliability)
+}
+
+func (s *WeightedSelector) reliabilityFor(sess *BroadcastSession) *reliability {
+	addr := ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient)
+	r, ok := s.reliability[addr]
+	if !ok {
+		r = &reliability{}
+		s.reliability[addr] = r
+	}
+	return r
+}
+
+// scoreSessions computes a composite score for every candidate session, normalizing
+// each criterion across the current pool so that weights remain meaningful regardless
+// of the underlying units
+func (s *WeightedSelector) scoreSessions() ([]float64, bool) {
+	stakes, ok := s.stakes()
+	if !ok {
+		return nil, false
+	}
+
+	latencies := make([]float64, len(s.sessions))
+	prices := make([]float64, len(s.sessions))
+	stakeVals := make([]float64, len(s.sessions))
+	reliabilities := make([]float64, len(s.sessions))
+
+	for i, sess := range s.sessions {
+		latencies[i] = sess.LatencyScore
+		prices[i] = priceOf(sess)
+		addr := ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient)
+		stakeVals[i] = float64(stakes[addr])
+		reliabilities[i] = s.reliabilityFor(sess).score()
+	}
+
+	// A LatencyScore of 0 means the session hasn't been measured yet. Left as-is it would
+	// look like the best possible latency once normalized, so fill it with the pool's mean
+	// measured latency instead, giving unmeasured sessions a neutral prior
+	fillUnmeasuredLatencies(latencies)
+
+	normLatency := normalizeLowerIsBetter(latencies)
+	normPrice := normalizeLowerIsBetter(prices)
+	normStake := normalizeHigherIsBetter(stakeVals)
+
+	scores := make([]float64, len(s.sessions))
+	for i := range s.sessions {
+		scores[i] = s.cfg.LatencyWeight*normLatency[i] +
+			s.cfg.PriceWeight*normPrice[i] +
+			s.cfg.StakeWeight*normStake[i] +
+			s.cfg.ReliabilityWeight*reliabilities[i]
+	}
+
+	return scores, true
+}
+
+// fillUnmeasuredLatencies replaces the zero-value LatencyScore of sessions that have not
+// yet been measured with the mean of the sessions that have, so that a brand-new session
+// is treated as average rather than unfairly ranked best by normalizeLowerIsBetter
+func fillUnmeasuredLatencies(latencies []float64) {
+	sum := 0.0
+	measured := 0
+	for _, l := range latencies {
+		if l > 0 {
+			sum += l
+			measured++
+		}
+	}
+
+	// If nothing in the pool has been measured yet there is no prior to fall back on, so
+	// leave the scores as-is; they will all normalize to the same neutral value anyway
+	if measured == 0 || measured == len(latencies) {
+		return
+	}
+
+	mean := sum / float64(measured)
+	for i, l := range latencies {
+		if l == 0 {
+			latencies[i] = mean
+		}
+	}
+}
+
+func (s *WeightedSelector) stakes() (map[ethcommon.Address]int64, bool) {
+	if s.stakeRdr == nil {
+		return make(map[ethcommon.Address]int64), true
+	}
+
+	addrs := make([]ethcommon.Address, len(s.sessions))
+	for i, sess := range s.sessions {
+		addrs[i] = ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient)
+	}
+
+	stakes, err := s.stakeRdr.Stakes(addrs)
+	if err != nil {
+		glog.Errorf("failed to read stake weights for selection: %v", err)
+		return nil, false
+	}
+
+	return stakes, true
+}
+
+// sample draws a session index from a softmax distribution over scores. A Temperature
+// <= 0 deterministically picks the highest scoring session
+func (s *WeightedSelector) sample(scores []float64) int {
+	if s.cfg.Temperature <= 0 {
+		best := 0
+		for i, score := range scores {
+			if score > scores[best] {
+				best = i
+			}
+		}
+		return best
+	}
+
+	weights := make([]float64, len(scores))
+	max := scores[0]
+	for _, score := range scores {
+		if score > max {
+			max = score
+		}
+	}
+
+	total := 0.0
+	for i, score := range scores {
+		// Subtract max before exponentiating for numerical stability
+		weights[i] = math.Exp((score - max) / s.cfg.Temperature)
+		total += weights[i]
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return i
+		}
+	}
+
+	return len(weights) - 1
+}
+
+func priceOf(sess *BroadcastSession) float64 {
+	price := sess.OrchestratorInfo.GetPriceInfo()
+	if price == nil || price.PixelsPerUnit == 0 {
+		return 0
+	}
+	return float64(price.PricePerUnit) / float64(price.PixelsPerUnit)
+}
+
+// normalizeLowerIsBetter maps vals to [0, 1] such that the lowest value scores 1 and the
+// highest value scores 0. A pool with no spread scores every value 1
+func normalizeLowerIsBetter(vals []float64) []float64 {
+	min, max := minMax(vals)
+	out := make([]float64, len(vals))
+	for i, v := range vals {
+		if max == min {
+			out[i] = 1
+			continue
+		}
+		out[i] = 1 - (v-min)/(max-min)
+	}
+	return out
+}
+
+// normalizeHigherIsBetter maps vals to [0, 1] such that the highest value scores 1 and the
+// lowest value scores 0. A pool with no spread scores every value 1
+func normalizeHigherIsBetter(vals []float64) []float64 {
+	min, max := minMax(vals)
+	out := make([]float64, len(vals))
+	for i, v := range vals {
+		if max == min {
+			out[i] = 1
+			continue
+		}
+		out[i] = (v - min) / (max - min)
+	}
+	return out
+}
+
+func minMax(vals []float64) (float64, float64) {
+	min, max := vals[0], vals[0]
+	for _, v := range vals[1:] {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	return min, max
+}
+
+// SelectorObserver receives notifications about BroadcastSessionsSelector events so that
+// operators can wire in metrics, e.g. Prometheus counters for selection latency,
+// unknown-vs-known ratios and stake-weighting outcomes, without modifying core selector logic
+type SelectorObserver interface {
+	// OnSelect is called with the result of a Select() call, which may be nil
+	OnSelect(sess *BroadcastSession)
+	// OnComplete is called when a session is reported as successfully used
+	OnComplete(sess *BroadcastSession)
+	// OnAdd is called with the number of sessions added to the selector
+	OnAdd(count int)
+	// OnClear is called when the selector's state is reset
+	OnClear()
+}
+
+// SyncSelector wraps a BroadcastSessionsSelector with a mutex so that it can be shared
+// safely across goroutines, e.g. for parallel segment dispatch
+type SyncSelector struct {
+	mu  sync.Mutex
+	sel BroadcastSessionsSelector
+	obs SelectorObserver
+}
+
+// NewSyncSelector returns a SyncSelector guarding sel. obs may be nil if no observer is needed
+func NewSyncSelector(sel BroadcastSessionsSelector, obs SelectorObserver) *SyncSelector {
+	return &SyncSelector{
+		sel: sel,
+		obs: obs,
+	}
+}
+
+// Add adds the sessions to the wrapped selector
+func (s *SyncSelector) Add(sessions []*BroadcastSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sel.Add(sessions)
+	if s.obs != nil {
+		s.obs.OnAdd(len(sessions))
+	}
+}
+
+// Complete reports a successful use of sess to the wrapped selector
+func (s *SyncSelector) Complete(sess *BroadcastSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sel.Complete(sess)
+	if s.obs != nil {
+		s.obs.OnComplete(sess)
+	}
+}
+
+// Failed reports a failed use of sess to the wrapped selector
+func (s *SyncSelector) Failed(sess *BroadcastSession, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sel.Failed(sess, err)
+}
+
+// Select returns the next session chosen by the wrapped selector
+func (s *SyncSelector) Select() *BroadcastSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess := s.sel.Select()
+	if s.obs != nil {
+		s.obs.OnSelect(sess)
+	}
+
+	return sess
+}
+
+// Size returns the number of sessions stored by the wrapped selector
+func (s *SyncSelector) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sel.Size()
+}
+
+// Clear resets the wrapped selector's state
+func (s *SyncSelector) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sel.Clear()
+	if s.obs != nil {
+		s.obs.OnClear()
+	}
+}
+
+// CircuitBreakerConfig configures the failure threshold and backoff schedule used by a
+// CircuitBreakerSelector
+type CircuitBreakerConfig struct {
+	// MaxConsecutiveFailures is the number of consecutive failures for an orchestrator
+	// after which its sessions are quarantined from selection
+	MaxConsecutiveFailures int
+	// BaseBackoff is the quarantine duration applied the first time the breaker trips.
+	// Each subsequent trip doubles the previous duration
+	BaseBackoff time.Duration
+	// MaxBackoff caps the exponentially increasing quarantine duration
+	MaxBackoff time.Duration
+}
+
+// DefaultCircuitBreakerConfig is a CircuitBreakerConfig for NewCircuitBreakerSelector.
+// NewCircuitBreakerSelector does not apply it on its own
+var DefaultCircuitBreakerConfig = CircuitBreakerConfig{
+	MaxConsecutiveFailures: 3,
+	BaseBackoff:            30 * time.Second,
+	MaxBackoff:             30 * time.Minute,
+}
+
+// breakerEntry tracks the consecutive failure count and quarantine state for a single
+// orchestrator address. trips counts how many times the breaker has tripped for this
+// address and persists across re-admissions so that backoff keeps escalating; it is only
+// reset by a successful Complete
+type breakerEntry struct {
+	sess                *BroadcastSession
+	consecutiveFailures int
+	trips               int
+	quarantinedUntil    time.Time
+	permanent           bool
+}
+
+// CircuitBreakerSelector decorates a BroadcastSessionsSelector so that an orchestrator
+// returning repeated errors is temporarily removed from the selection pool instead of
+// being immediately re-added by callers. After MaxConsecutiveFailures failures in a row,
+// the orchestrator's session is quarantined for an exponentially increasing duration;
+// errors wrapping ErrOrchestratorProtocol are treated as permanent and are not re-admitted.
+// CircuitBreakerSelector is not concurrency safe; wrap it with SyncSelector to share it
+// across goroutines
+type CircuitBreakerSelector struct {
+	sel BroadcastSessionsSelector
+	cfg CircuitBreakerConfig
+
+	quarantine map[ethcommon.Address]*breakerEntry
+}
+
+// NewCircuitBreakerSelector returns a CircuitBreakerSelector decorating sel with cfg
+func NewCircuitBreakerSelector(sel BroadcastSessionsSelector, cfg CircuitBreakerConfig) *CircuitBreakerSelector {
+	return &CircuitBreakerSelector{
+		sel:        sel,
+		cfg:        cfg,
+		quarantine: make(map[ethcommon.Address]*breakerEntry),
+	}
+}
+
+// Add adds the sessions to the wrapped selector
+func (s *CircuitBreakerSelector) Add(sessions []*BroadcastSession) {
+	s.sel.Add(sessions)
+}
+
+// Complete resets the failure count for sess's orchestrator and reports success to the
+// wrapped selector
+func (s *CircuitBreakerSelector) Complete(sess *BroadcastSession) {
+	delete(s.quarantine, circuitBreakerAddr(sess))
+	s.sel.Complete(sess)
+}
+
+// Failed records a failure for sess's orchestrator. Once MaxConsecutiveFailures is
+// reached the session is held out of the wrapped selector's pool until its quarantine
+// expires instead of being passed through to sel.Failed. The consecutive failure count
+// resets after each trip so that the next MaxConsecutiveFailures failures trigger another,
+// longer trip; the trip count itself persists until Complete reports a success
+func (s *CircuitBreakerSelector) Failed(sess *BroadcastSession, err error) {
+	addr := circuitBreakerAddr(sess)
+
+	entry, ok := s.quarantine[addr]
+	if !ok {
+		entry = &breakerEntry{}
+		s.quarantine[addr] = entry
+	}
+	entry.consecutiveFailures++
+
+	if errors.Is(err, ErrOrchestratorProtocol) {
+		entry.sess = sess
+		entry.permanent = true
+		return
+	}
+
+	if entry.consecutiveFailures < s.cfg.MaxConsecutiveFailures {
+		s.sel.Failed(sess, err)
+		return
+	}
+
+	entry.consecutiveFailures = 0
+	entry.trips++
+	entry.sess = sess
+	entry.quarantinedUntil = time.Now().Add(s.backoff(entry.trips))
+}
+
+// backoff returns the exponentially increasing quarantine duration for the given trip
+// count, capped at MaxBackoff. The first trip quarantines for BaseBackoff and each
+// subsequent trip doubles the previous duration
+func (s *CircuitBreakerSelector) backoff(trips int) time.Duration {
+	d := float64(s.cfg.BaseBackoff) * math.Pow(2, float64(trips-1))
+	if d <= 0 || d > float64(s.cfg.MaxBackoff) {
+		return s.cfg.MaxBackoff
+	}
+
+	return time.Duration(d)
+}
+
+// Select re-admits any sessions whose quarantine has expired and then selects from the
+// wrapped selector
+func (s *CircuitBreakerSelector) Select() *BroadcastSession {
+	s.readmitExpired()
+
+	return s.sel.Select()
+}
+
+// readmitExpired returns sessions whose quarantine window has passed to the wrapped
+// selector's pool. The breakerEntry itself is kept (not deleted) so that entry.trips
+// keeps escalating the backoff if the orchestrator fails again; only a successful
+// Complete clears an entry entirely
+func (s *CircuitBreakerSelector) readmitExpired() {
+	now := time.Now()
+
+	for _, entry := range s.quarantine {
+		if entry.sess == nil || entry.permanent || now.Before(entry.quarantinedUntil) {
+			continue
+		}
+
+		s.sel.Add([]*BroadcastSession{entry.sess})
+		entry.sess = nil
+		entry.quarantinedUntil = time.Time{}
+	}
+}
+
+// Size returns the number of sessions stored by the wrapped selector. Quarantined
+// sessions are not counted since they are not eligible for selection
+func (s *CircuitBreakerSelector) Size() int {
+	return s.sel.Size()
+}
+
+// Clear resets the wrapped selector's state and clears all quarantines
+func (s *CircuitBreakerSelector) Clear() {
+	s.sel.Clear()
+	s.quarantine = make(map[ethcommon.Address]*breakerEntry)
+}
+
+func circuitBreakerAddr(sess *BroadcastSession) ethcommon.Address {
+	return ethcommon.BytesToAddress(sess.OrchestratorInfo.TicketParams.Recipient)
+}
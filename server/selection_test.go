@@ -0,0 +1,94 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/livepeer/go-livepeer/net"
+)
+
+func newTestSession(addr byte) *BroadcastSession {
+	return &BroadcastSession{
+		OrchestratorInfo: &net.OrchestratorInfo{
+			TicketParams: &net.TicketParams{
+				Recipient: []byte{addr},
+			},
+		},
+	}
+}
+
+func TestMinLSSelector_EWMAOneStep(t *testing.T) {
+	sel := NewMinLSSelector(nil, 10, WithAlpha(0.3))
+	sess := newTestSession(1)
+
+	sess.LatencyScore = 10
+	sel.updateHistory(sess)
+
+	sess.LatencyScore = 20
+	got := sel.updateHistory(sess)
+
+	want := 0.3*20 + 0.7*10
+	if got != want {
+		t.Errorf("expected EWMA score %v, got %v", want, got)
+	}
+}
+
+func TestMinLSSelector_EWMAConvergence(t *testing.T) {
+	sel := NewMinLSSelector(nil, 10, WithAlpha(0.3))
+	sess := newTestSession(2)
+
+	sess.LatencyScore = 100
+	score := sel.updateHistory(sess)
+
+	const target = 5.0
+	for i := 0; i < 50; i++ {
+		sess.LatencyScore = target
+		score = sel.updateHistory(sess)
+	}
+
+	if diff := score - target; diff > 0.01 || diff < -0.01 {
+		t.Errorf("expected EWMA score to converge to %v after repeated sampling, got %v", target, score)
+	}
+}
+
+func TestMinLSSelector_DecayToUnknown(t *testing.T) {
+	sel := NewMinLSSelector(nil, 10, WithTTL(10*time.Millisecond))
+
+	sess := newTestSession(3)
+	sess.LatencyScore = 1.0
+	sel.Complete(sess)
+
+	if sel.knownSessions.Len() != 1 {
+		t.Fatalf("expected 1 known session, got %d", sel.knownSessions.Len())
+	}
+	if len(sel.unknownSessions) != 0 {
+		t.Fatalf("expected 0 unknown sessions, got %d", len(sel.unknownSessions))
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	sel.expireStale()
+
+	if sel.knownSessions.Len() != 0 {
+		t.Errorf("expected stale session to be removed from knownSessions, got %d remaining", sel.knownSessions.Len())
+	}
+	if len(sel.unknownSessions) != 1 {
+		t.Errorf("expected stale session to re-enter unknownSessions, got %d", len(sel.unknownSessions))
+	}
+}
+
+func TestMinLSSelector_NoDecayBeforeTTL(t *testing.T) {
+	sel := NewMinLSSelector(nil, 10, WithTTL(time.Minute))
+
+	sess := newTestSession(4)
+	sess.LatencyScore = 1.0
+	sel.Complete(sess)
+
+	sel.expireStale()
+
+	if sel.knownSessions.Len() != 1 {
+		t.Errorf("expected session to remain known before its TTL elapses, got %d known", sel.knownSessions.Len())
+	}
+	if len(sel.unknownSessions) != 0 {
+		t.Errorf("expected no unknown sessions before TTL elapses, got %d", len(sel.unknownSessions))
+	}
+}